@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+// ErrorCode identifies the kind of a ValidationError, stably and without
+// allocation, so callers can switch on the failure instead of
+// string-matching Error(). Codes are constants, never formatted strings.
+type ErrorCode string
+
+const (
+	CodeType                 ErrorCode = "Type"
+	CodeRequired             ErrorCode = "Required"
+	CodePattern              ErrorCode = "Pattern"
+	CodePatternProperty      ErrorCode = "PatternProperty"
+	CodeAdditionalProperties ErrorCode = "AdditionalProperties"
+	CodeMinLength            ErrorCode = "MinLength"
+	CodeFormatRangeOverflow  ErrorCode = "FormatRangeOverflow"
+	CodeFormatInt32Overflow  ErrorCode = "FormatInt32Overflow"
+	CodeFormatMismatch       ErrorCode = "FormatMismatch"
+	CodeRefUnresolved        ErrorCode = "RefUnresolved"
+	CodeAnyOf                ErrorCode = "AnyOf"
+	CodeOneOf                ErrorCode = "OneOf"
+)
+
+// Locale renders a localized message for an error code given its
+// parameters. A nil Locale (the default) falls back to ValidationError's
+// built-in English Message.
+type Locale func(code ErrorCode, params map[string]interface{}) string
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestResult_ErrorsByCode(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"name": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"age":  {SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Format: "int32"}},
+			},
+			Required: []string{"name"},
+		},
+	}
+
+	input := map[string]interface{}{"age": int64(1 << 40)}
+
+	validator := NewSchemaValidator(schema, nil, "", strfmt.Default, WithMultiError())
+	res := validator.Validate(input)
+	require.NotEmpty(t, res.Errors)
+
+	required := res.ErrorsByCode(CodeRequired)
+	assert.Len(t, required, 1)
+
+	overflow := res.ErrorsByCode(CodeFormatInt32Overflow)
+	assert.Len(t, overflow, 1)
+
+	assert.Empty(t, res.ErrorsByCode(CodePattern))
+}
+
+func TestValidationError_SchemaPath(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]spec.Schema{
+				"tags": {SchemaProps: spec.SchemaProps{
+					Type:  []string{"array"},
+					Items: &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}}},
+				}},
+			},
+			Required: []string{"name"},
+		},
+	}
+
+	input := map[string]interface{}{"tags": []interface{}{"ok", 42}}
+
+	validator := NewSchemaValidator(schema, nil, "", strfmt.Default, WithMultiError())
+	res := validator.Validate(input)
+	require.Len(t, res.Errors, 2)
+
+	required := res.ErrorsByCode(CodeRequired)
+	require.Len(t, required, 1)
+	assert.Equal(t, "required", required[0].(*ValidationError).SchemaPath)
+
+	typeErrs := res.ErrorsByCode(CodeType)
+	require.Len(t, typeErrs, 1)
+	assert.Equal(t, "properties.tags.items", typeErrs[0].(*ValidationError).SchemaPath)
+}
+
+func TestValidationError_Localized(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}, Pattern: "^[a-z]+$"}}
+	validator := NewSchemaValidator(schema, nil, "", strfmt.Default)
+
+	res := validator.Validate("NOPE")
+	require.Len(t, res.Errors, 1)
+
+	verr, ok := res.Errors[0].(*ValidationError)
+	require.True(t, ok)
+
+	// Default English message is stable and unaffected by a nil locale.
+	assert.Equal(t, verr.Message, verr.Localized(nil))
+
+	frenchLocale := Locale(func(code ErrorCode, params map[string]interface{}) string {
+		return fmt.Sprintf("ne correspond pas au motif %v", params["pattern"])
+	})
+	assert.Contains(t, verr.Localized(frenchLocale), "motif")
+
+	// Error() stays stable regardless of any Locale.
+	assert.Equal(t, `does not match pattern "^[a-z]+$"`, verr.Error())
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import "fmt"
+
+// ValidationError is the error type produced for every schema validation
+// failure in this package. Error() keeps a stable, English string (don't
+// parse it); structured consumers should use Code, InstancePath, SchemaPath,
+// Keyword, Value and Params instead, optionally rendered through a Locale
+// via Localized.
+type ValidationError struct {
+	Code ErrorCode
+
+	// InstancePath is the path into the validated document (dotted, root
+	// is "").
+	InstancePath string
+	// SchemaPath is the path into the schema that produced this error.
+	SchemaPath string
+	// Keyword is the JSON Schema keyword that failed (e.g. "pattern").
+	Keyword string
+	// Value is the offending instance value.
+	Value interface{}
+	// Params carries the keyword's parameters (e.g. {"pattern": "^a+$"}),
+	// for use by a Locale or by structured consumers.
+	Params map[string]interface{}
+
+	Message string
+	cause   error
+}
+
+func (e *ValidationError) Error() string {
+	if e.InstancePath == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.InstancePath, e.Message)
+}
+
+func (e *ValidationError) Unwrap() error { return e.cause }
+
+// Localized renders this error through l, falling back to the default
+// English Message when l is nil.
+func (e *ValidationError) Localized(l Locale) string {
+	if l == nil {
+		return e.Message
+	}
+	return l(e.Code, e.Params)
+}
+
+func newTypeError(path, schemaPath string, want []string, got interface{}) *ValidationError {
+	return &ValidationError{
+		Code:         CodeType,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "type",
+		Value:        got,
+		Params:       map[string]interface{}{"want": want},
+		Message:      fmt.Sprintf("expected type %v, got %T", want, got),
+	}
+}
+
+func newRequiredError(path, schemaPath string) *ValidationError {
+	return &ValidationError{
+		Code:         CodeRequired,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "required",
+		Message:      "is required",
+	}
+}
+
+func newPatternError(path, schemaPath, pattern string, code ErrorCode) *ValidationError {
+	return &ValidationError{
+		Code:         code,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "pattern",
+		Params:       map[string]interface{}{"pattern": pattern},
+		Message:      fmt.Sprintf("does not match pattern %q", pattern),
+	}
+}
+
+func newMinLengthError(path, schemaPath string, min int64) *ValidationError {
+	return &ValidationError{
+		Code:         CodeMinLength,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "minLength",
+		Params:       map[string]interface{}{"minLength": min},
+		Message:      fmt.Sprintf("shorter than minLength %d", min),
+	}
+}
+
+func newAdditionalPropertiesError(path, schemaPath, key string) *ValidationError {
+	return &ValidationError{
+		Code:         CodeAdditionalProperties,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "additionalProperties",
+		Params:       map[string]interface{}{"property": key},
+		Message:      fmt.Sprintf("additional property %q is not allowed", key),
+	}
+}
+
+// formatOverflowCode picks the most specific ErrorCode available for a
+// format range failure, falling back to the generic overflow code.
+func formatOverflowCode(format string) ErrorCode {
+	if format == "int32" {
+		return CodeFormatInt32Overflow
+	}
+	return CodeFormatRangeOverflow
+}
+
+func newFormatRangeError(path, schemaPath, format string) *ValidationError {
+	return &ValidationError{
+		Code:         formatOverflowCode(format),
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "format",
+		Params:       map[string]interface{}{"format": format},
+		Message:      fmt.Sprintf("value out of range for format %q", format),
+	}
+}
+
+func newFormatMismatchError(path, schemaPath, format, wantType string) *ValidationError {
+	return &ValidationError{
+		Code:         CodeFormatMismatch,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "format",
+		Params:       map[string]interface{}{"format": format, "wantType": wantType},
+		Message:      fmt.Sprintf("format %q requires type %q (set FormatEnforcementPolicy to Lenient to ignore)", format, wantType),
+	}
+}
+
+func newAnyOfError(path, schemaPath string) *ValidationError {
+	return &ValidationError{
+		Code:         CodeAnyOf,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "anyOf",
+		Message:      "does not match any of the schemas in anyOf",
+	}
+}
+
+func newOneOfError(path, schemaPath string, matched int) *ValidationError {
+	return &ValidationError{
+		Code:         CodeOneOf,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "oneOf",
+		Params:       map[string]interface{}{"matched": matched},
+		Message:      fmt.Sprintf("matched %d schemas in oneOf, want exactly 1", matched),
+	}
+}
+
+func newRefUnresolvedError(path, schemaPath, ref string, cause error) *ValidationError {
+	return &ValidationError{
+		Code:         CodeRefUnresolved,
+		InstancePath: path,
+		SchemaPath:   schemaPath,
+		Keyword:      "$ref",
+		Params:       map[string]interface{}{"ref": ref},
+		Message:      fmt.Sprintf("unresolved $ref %q", ref),
+		cause:        cause,
+	}
+}
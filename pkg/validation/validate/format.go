@@ -0,0 +1,284 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// FormatValidator checks a single named "format" keyword against an
+// arbitrary decoded instance, not just strings. KnownFormats (strfmt.Registry)
+// remains the home for string formats like date-time or email;
+// FormatValidator exists for formats whose domain is numeric, or otherwise
+// non-string.
+type FormatValidator interface {
+	// Applies reports whether this validator checks values shaped like
+	// value (kind is value's reflect.Kind, or reflect.Invalid for nil).
+	Applies(value interface{}, kind reflect.Kind) bool
+	// Validate checks value, returning a descriptive error if it's invalid.
+	Validate(value interface{}) error
+}
+
+// formatRegistry is a concurrent-safe registry of named FormatValidators.
+// Registration is allowed at any time, including after schemas referencing
+// the format have already been parsed and validators constructed against
+// them: every Validate call does a fresh lookup, so a long-running process
+// can hot-register a format without reparsing any schema.
+type formatRegistry struct {
+	mu         sync.RWMutex
+	validators map[string]FormatValidator
+}
+
+func newFormatRegistry() *formatRegistry {
+	r := &formatRegistry{validators: map[string]FormatValidator{}}
+	r.register("int", newIntRangeValidator(math.MinInt64, math.MaxInt64))
+	r.register("int32", newIntRangeValidator(math.MinInt32, math.MaxInt32))
+	r.register("int64", newIntRangeValidator(math.MinInt64, math.MaxInt64))
+	r.register("uint", newUintRangeValidator(0, math.MaxUint64))
+	r.register("uint32", newUintRangeValidator(0, math.MaxUint32))
+	r.register("uint64", newUintRangeValidator(0, math.MaxUint64))
+	r.register("byte", newIntRangeValidator(0, 255))
+	r.register("float", floatFormatValidator{bits: 32})
+	r.register("double", floatFormatValidator{bits: 64})
+	return r
+}
+
+func (r *formatRegistry) register(name string, v FormatValidator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validators[name] = v
+}
+
+func (r *formatRegistry) lookup(name string) (FormatValidator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.validators[name]
+	return v, ok
+}
+
+// globalFormats is the process-wide registry consulted by every
+// SchemaValidator for the numeric (and other non-string) formats this
+// package knows about.
+var globalFormats = newFormatRegistry()
+
+// RegisterFormat registers (or replaces) the FormatValidator used for the
+// named "format" keyword, process-wide. Safe for concurrent use, and safe
+// to call at any time -- including after schemas using that format have
+// already been validated.
+func RegisterFormat(name string, v FormatValidator) {
+	globalFormats.register(name, v)
+}
+
+// rangeFormatValidator rejects any numeric value outside [min, max]. Bounds
+// and the incoming value are both compared as big.Float, never round-tripped
+// through float64: float64(math.MaxInt64) itself already rounds up past the
+// real bound, so a float64 bound (even compared precisely) would still be
+// wrong at exactly the values this validator exists to catch.
+type rangeFormatValidator struct {
+	min, max *big.Float
+}
+
+// newIntRangeValidator builds a rangeFormatValidator from exact int64 bounds.
+func newIntRangeValidator(min, max int64) rangeFormatValidator {
+	return rangeFormatValidator{min: new(big.Float).SetInt64(min), max: new(big.Float).SetInt64(max)}
+}
+
+// newUintRangeValidator builds a rangeFormatValidator from exact uint64
+// bounds, for ranges (like uint64's own max) that overflow int64.
+func newUintRangeValidator(min, max uint64) rangeFormatValidator {
+	return rangeFormatValidator{min: new(big.Float).SetUint64(min), max: new(big.Float).SetUint64(max)}
+}
+
+func (rangeFormatValidator) Applies(value interface{}, _ reflect.Kind) bool {
+	_, numeric := asFloat64(value)
+	return numeric
+}
+
+func (v rangeFormatValidator) Validate(value interface{}) error {
+	if !inRange(value, v.min, v.max) {
+		return fmt.Errorf("value %v out of range [%v, %v]", value, v.min, v.max)
+	}
+	return nil
+}
+
+// inRange reports whether value falls within [min, max]. A json.Number is
+// parsed directly from its decimal text via big.Float, avoiding the
+// float64-rounding trap that a Float64()-then-compare path would hit at
+// exactly the boundary values (e.g. math.MaxInt64) this exists to catch.
+// Every other representation goes through asBigFloat, which also converts
+// exactly for the Go integer kinds.
+func inRange(value interface{}, min, max *big.Float) bool {
+	bf, ok := asBigFloat(value)
+	if !ok {
+		return false
+	}
+	return bf.Cmp(min) >= 0 && bf.Cmp(max) <= 0
+}
+
+// asBigFloat is asFloat64's exact counterpart: it extracts a *big.Float
+// without rounding through float64 for json.Number and the Go integer kinds,
+// falling back to float64 only for the Go float kinds (which are already
+// imprecise at the call site).
+func asBigFloat(value interface{}) (*big.Float, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		bf, _, err := big.ParseFloat(string(v), 10, 0, big.ToNearestEven)
+		if err != nil {
+			return nil, false
+		}
+		return bf, true
+	case int:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int8:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int16:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int32:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int64:
+		return new(big.Float).SetInt64(v), true
+	case uint:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint8:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint16:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint32:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint64:
+		return new(big.Float).SetUint64(v), true
+	case float64:
+		return big.NewFloat(v), true
+	case float32:
+		return big.NewFloat(float64(v)), true
+	default:
+		return nil, false
+	}
+}
+
+// floatFormatValidator rejects non-finite values, and (for bits == 32)
+// values that overflow float32.
+type floatFormatValidator struct {
+	bits int
+}
+
+func (floatFormatValidator) Applies(value interface{}, _ reflect.Kind) bool {
+	_, numeric := asFloat64(value)
+	return numeric
+}
+
+func (v floatFormatValidator) Validate(value interface{}) error {
+	f, ok := asFloat64(value)
+	if !ok {
+		return fmt.Errorf("value is not numeric")
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("value %v must be finite", f)
+	}
+	if v.bits == 32 && (f > math.MaxFloat32 || f < -math.MaxFloat32) {
+		return fmt.Errorf("value %v overflows float32", f)
+	}
+	return nil
+}
+
+// durationWeekPattern matches the RFC 3339 (ISO 8601) week form of a
+// duration, e.g. "P2W". Per RFC 3339 Appendix A, dur-week is mutually
+// exclusive with both the date and time forms -- "P1W2D" is not valid.
+var durationWeekPattern = regexp.MustCompile(`^P\d+W$`)
+
+// durationPattern matches the RFC 3339 (ISO 8601) date/time form of a
+// duration: "P", followed by an optional count of years/months/days,
+// optionally followed by "T" and a count of hours/minutes/(possibly
+// fractional) seconds -- e.g. "P3Y6M4DT12H30M5S" or "PT1H30M". At least one
+// designator must be present, so "P" alone, and a "T" with nothing after
+// it, are both rejected below.
+var durationPattern = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+
+// isRFC3339Duration reports whether s is a valid RFC 3339 duration string,
+// as opposed to Go's own (and syntactically unrelated) time.ParseDuration
+// grammar ("300ms", "1.5h").
+func isRFC3339Duration(s string) bool {
+	if durationWeekPattern.MatchString(s) {
+		return true
+	}
+
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	hasDate := m[1] != "" || m[2] != "" || m[3] != ""
+	hasTime := m[5] != "" || m[6] != "" || m[7] != ""
+	if m[4] != "" && !hasTime {
+		// A "T" with no hour/minute/second designator after it.
+		return false
+	}
+	return hasDate || hasTime
+}
+
+// DurationFormatValidator validates a "duration" format: either an RFC 3339
+// (ISO 8601) duration string such as "PT1H30M", or an int64 count of
+// nanoseconds. It is not registered by default; opt in with
+// RegisterFormat("duration", ...).
+type DurationFormatValidator struct{}
+
+func (DurationFormatValidator) Applies(value interface{}, kind reflect.Kind) bool {
+	switch value.(type) {
+	case string, int64:
+		return true
+	}
+	return kind == reflect.String || kind == reflect.Int64
+}
+
+func (DurationFormatValidator) Validate(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if !isRFC3339Duration(v) {
+			return fmt.Errorf("invalid RFC 3339 duration %q", v)
+		}
+		return nil
+	case int64:
+		return nil
+	default:
+		return fmt.Errorf("duration format requires an RFC 3339 duration string or an int64 nanosecond count, got %T", value)
+	}
+}
+
+// PortFormatValidator validates a "port" format: an integer in 1..65535. It
+// is not registered by default; opt in with RegisterFormat("port", ...).
+type PortFormatValidator struct{}
+
+func (PortFormatValidator) Applies(value interface{}, _ reflect.Kind) bool {
+	_, numeric := asFloat64(value)
+	return numeric
+}
+
+func (PortFormatValidator) Validate(value interface{}) error {
+	f, ok := asFloat64(value)
+	if !ok || f != math.Trunc(f) {
+		return fmt.Errorf("port must be an integer")
+	}
+	if f < 1 || f > 65535 {
+		return fmt.Errorf("port %v out of range 1..65535", f)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationFormatValidator(t *testing.T) {
+	v := DurationFormatValidator{}
+	assert.NoError(t, v.Validate("PT1H30M"))
+	assert.NoError(t, v.Validate("P3Y6M4DT12H30M5S"))
+	assert.NoError(t, v.Validate("P1D"))
+	assert.NoError(t, v.Validate("P2W"))
+	assert.NoError(t, v.Validate(int64(1500000000)))
+	assert.Error(t, v.Validate("1h30m"), "Go's time.ParseDuration syntax is not RFC 3339")
+	assert.Error(t, v.Validate("P"))
+	assert.Error(t, v.Validate("PT"))
+	assert.Error(t, v.Validate("P1W2D"), "dur-week is mutually exclusive with dur-date/dur-time")
+	assert.Error(t, v.Validate("not-a-duration"))
+	assert.Error(t, v.Validate(3.14))
+}
+
+func TestPortFormatValidator(t *testing.T) {
+	v := PortFormatValidator{}
+	assert.NoError(t, v.Validate(int64(8080)))
+	assert.Error(t, v.Validate(int64(0)))
+	assert.Error(t, v.Validate(int64(70000)))
+	assert.Error(t, v.Validate(8080.5))
+}
+
+func TestRegisterFormat_ConcurrentAndHot(t *testing.T) {
+	// Registration must be safe to call concurrently, and after schemas
+	// referencing the format have already been validated.
+	RegisterFormat("port", PortFormatValidator{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RegisterFormat("duration", DurationFormatValidator{})
+			_, _ = globalFormats.lookup("duration")
+		}()
+	}
+	wg.Wait()
+
+	fv, ok := globalFormats.lookup("port")
+	assert.True(t, ok)
+	assert.NoError(t, fv.Validate(int64(443)))
+}
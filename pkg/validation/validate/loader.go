@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Loader fetches the raw bytes of the document backing a $ref, for one URI
+// scheme. Implementations are registered on a SchemaLoader via WithLoader.
+type Loader interface {
+	// Load returns the raw JSON document identified by uri (the $ref with
+	// any "#/..." fragment already stripped).
+	Load(uri string) ([]byte, error)
+}
+
+// SchemaLoader resolves $ref URIs to spec.Schema values. It dispatches to a
+// Loader registered per URI scheme for document retrieval, parses each
+// document once, and caches it by URI so repeated refs into the same
+// document don't re-fetch or re-parse it.
+type SchemaLoader struct {
+	loaders map[string]Loader
+	cache   map[string]*spec.Schema
+}
+
+// NewSchemaLoader creates a SchemaLoader with the default "file", "http" and
+// "https" loaders registered.
+func NewSchemaLoader() *SchemaLoader {
+	return &SchemaLoader{
+		loaders: map[string]Loader{
+			"file":  FileLoader{},
+			"http":  HTTPLoader{},
+			"https": HTTPLoader{},
+		},
+		cache: map[string]*spec.Schema{},
+	}
+}
+
+// WithLoader registers (or replaces) the Loader used for the given URI
+// scheme and returns l for chaining.
+func (l *SchemaLoader) WithLoader(scheme string, loader Loader) *SchemaLoader {
+	l.loaders[scheme] = loader
+	return l
+}
+
+// Load resolves ref to the spec.Schema it points at. A ref starting with "#"
+// is resolved as a JSON pointer fragment against root (the in-process
+// document the referencing schema came from); any other ref is treated as an
+// absolute URI and dispatched to the Loader registered for its scheme.
+//
+// Load also returns the document the ref actually resolved within: root
+// itself for a "#/..." ref, or the newly fetched (or cached) document for an
+// absolute ref. Callers following further refs nested inside the result must
+// resolve those against this returned document, not the original root -- a
+// ref inside an externally-fetched document is never relative to whatever
+// document referenced it in the first place.
+func (l *SchemaLoader) Load(ref string, root interface{}) (*spec.Schema, interface{}, error) {
+	if strings.HasPrefix(ref, "#") {
+		resolved, err := resolveFragment(root, strings.TrimPrefix(ref, "#"))
+		return resolved, root, err
+	}
+
+	docURI, fragment := splitFragment(ref)
+
+	if cached, ok := l.cache[docURI]; ok {
+		resolved, err := resolveFragment(cached, fragment)
+		return resolved, cached, err
+	}
+
+	u, err := url.Parse(docURI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid $ref %q: %w", ref, err)
+	}
+	loader, ok := l.loaders[u.Scheme]
+	if !ok {
+		return nil, nil, fmt.Errorf("no Loader registered for scheme %q in $ref %q", u.Scheme, ref)
+	}
+
+	raw, err := loader.Load(docURI)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc := new(spec.Schema)
+	if err := json.Unmarshal(raw, doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing document %q: %w", docURI, err)
+	}
+	l.cache[docURI] = doc
+
+	resolved, err := resolveFragment(doc, fragment)
+	return resolved, doc, err
+}
+
+func splitFragment(ref string) (uri, fragment string) {
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// resolveFragment walks a "/definitions/foo"-style JSON pointer fragment
+// against an already-parsed document root.
+func resolveFragment(root interface{}, fragment string) (*spec.Schema, error) {
+	fragment = strings.Trim(fragment, "/")
+
+	doc, ok := root.(*spec.Schema)
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve fragment %q against root of type %T", fragment, root)
+	}
+	if fragment == "" {
+		return doc, nil
+	}
+
+	parts := strings.Split(fragment, "/")
+	if len(parts) == 2 && parts[0] == "definitions" && doc.Definitions != nil {
+		if def, ok := doc.Definitions[parts[1]]; ok {
+			return &def, nil
+		}
+	}
+	return nil, fmt.Errorf("definition %q not found", fragment)
+}
+
+// FileLoader loads a document from the local filesystem for "file://" URIs.
+// Percent-encoded paths (e.g. "%20" for a space) are decoded before the file
+// is opened, since url.Parse already unescapes Path for us.
+type FileLoader struct{}
+
+func (FileLoader) Load(uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file URI %q: %w", uri, err)
+	}
+	path := u.Path
+	if path == "" {
+		path = u.Opaque
+	}
+	return os.ReadFile(path)
+}
+
+// HTTPLoader loads a document over "http://" or "https://". A nil Client
+// uses http.DefaultClient.
+type HTTPLoader struct {
+	Client *http.Client
+}
+
+func (h HTTPLoader) Load(uri string) ([]byte, error) {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %q: unexpected status %s", uri, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestFileLoader_PercentEncodedSpace(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "path with space")
+	require.NoError(t, os.Mkdir(sub, 0o755))
+
+	const doc = `{"type": "string"}`
+	file := filepath.Join(sub, "foo.json")
+	require.NoError(t, os.WriteFile(file, []byte(doc), 0o644))
+
+	u := url.URL{Scheme: "file", Path: file}
+
+	raw, err := (FileLoader{}).Load(u.String())
+	require.NoError(t, err)
+	assert.JSONEq(t, doc, string(raw))
+}
+
+func TestSchemaLoader_UnknownScheme(t *testing.T) {
+	loader := NewSchemaLoader()
+	_, _, err := loader.Load("ftp://example.com/schema.json", nil)
+	assert.Error(t, err)
+}
+
+func TestSchemaLoader_NestedRefFollowsFetchedDocumentRoot(t *testing.T) {
+	dir := t.TempDir()
+
+	// doc.json is fetched externally and itself contains a nested $ref:
+	// "first" points at "second", both defined in doc.json. Resolving that
+	// nested ref must walk doc.json's own definitions (the document it was
+	// just fetched from), not the validator's original Root, which has no
+	// definitions at all.
+	const doc = `{
+		"definitions": {
+			"first": {"$ref": "#/definitions/second"},
+			"second": {"type": "string"}
+		}
+	}`
+	docPath := filepath.Join(dir, "doc.json")
+	require.NoError(t, os.WriteFile(docPath, []byte(doc), 0o644))
+
+	docURI := (&url.URL{Scheme: "file", Path: docPath}).String()
+	schemaJSON := fmt.Sprintf(`{"$ref": %q}`, docURI+"#/definitions/first")
+
+	schema := new(spec.Schema)
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), schema))
+
+	loader := NewSchemaLoader()
+	s := NewSchemaValidator(schema, schema, "", strfmt.Default, WithSchemaLoader(loader))
+
+	assert.True(t, s.Validate("a string").IsValid())
+	assert.False(t, s.Validate(42).IsValid())
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"errors"
+	"strings"
+)
+
+// MultiError collects every error found while validating a single document
+// under WithMultiError. It implements Unwrap() []error so errors.Is/errors.As
+// see every wrapped error, and a custom Is so errors.Is(multiErr, target)
+// reports true as soon as any wrapped error matches target.
+type MultiError struct {
+	Errors []error
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap exposes every collected error to errors.Is/errors.As.
+func (m *MultiError) Unwrap() []error { return m.Errors }
+
+// Is reports true if target matches any of the wrapped errors, so callers
+// can write errors.Is(err, SomeSentinel) without caring whether err is a
+// single ValidationError or a MultiError wrapping several.
+func (m *MultiError) Is(target error) bool {
+	for _, err := range m.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
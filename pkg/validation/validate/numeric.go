@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// asFloat64 extracts a float64 from any of the numeric representations the
+// validator may see: the concrete Go numeric kinds produced by typed
+// callers, and json.Number for callers decoding with UseNumber.
+func asFloat64(data interface{}) (float64, bool) {
+	switch v := data.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// integralBounds caps the magnitude isIntegral will accept: every concrete
+// integer type this package range-checks (format.go's int/uint validators)
+// fits in [math.MinInt64, math.MaxUint64], so a value outside that band
+// isn't "integer" in any sense a caller could actually store it as, whatever
+// its decimal text looks like.
+var (
+	minIntegralMagnitude = new(big.Float).SetInt64(math.MinInt64)
+	maxIntegralMagnitude = new(big.Float).SetUint64(math.MaxUint64)
+)
+
+// isIntegral reports whether data is a whole number, in range for some
+// concrete integer type. It compares the exact decimal value (via
+// asBigFloat, never rounding through float64) rather than asFloat64's
+// f == math.Trunc(f), which trivially passes for any magnitude beyond
+// float64's ~2^53 exact-integer range.
+func isIntegral(data interface{}) bool {
+	bf, ok := asBigFloat(data)
+	if !ok {
+		return false
+	}
+	return bf.IsInt() && bf.Cmp(minIntegralMagnitude) >= 0 && bf.Cmp(maxIntegralMagnitude) <= 0
+}
+
+// formatRequiredType maps a registered numeric format to the sole schema
+// type it applies under (today's Lenient-only policy): a mismatched pair
+// like {"type":"number","format":"int32"} is ambiguous and left unenforced.
+// Formats with no entry here (e.g. "duration", "port") apply regardless of
+// schema.Type.
+var formatRequiredType = map[string]string{
+	"int":    "integer",
+	"int32":  "integer",
+	"int64":  "integer",
+	"uint":   "integer",
+	"uint32": "integer",
+	"uint64": "integer",
+	"byte":   "integer",
+	"float":  "number",
+	"double": "number",
+}
+
+// validateFormat enforces the format registered for schema.Format (see
+// format.go) when the format applies to data's kind. Whether a mismatched
+// type/format pair (e.g. {"type":"number","format":"int32"}) is ignored,
+// rejected, or merely warned about is controlled by FormatEnforcementPolicy.
+func (c *validateCtx) validateFormat(path, schemaPath string, schema *spec.Schema, data interface{}) {
+	if schema.Format == "" {
+		return
+	}
+
+	fv, ok := globalFormats.lookup(schema.Format)
+	if !ok {
+		return
+	}
+
+	formatSchemaPath := joinPath(schemaPath, "format")
+
+	if want, ok := formatRequiredType[schema.Format]; ok && !isSoleType(schema, want) {
+		switch c.validator.Options.formatPolicy {
+		case Strict:
+			c.fail(newFormatMismatchError(path, formatSchemaPath, schema.Format, want))
+		case Warn:
+			c.result.AddWarnings(newFormatMismatchError(path, formatSchemaPath, schema.Format, want))
+		}
+		return
+	}
+
+	if !fv.Applies(data, reflect.ValueOf(data).Kind()) {
+		return
+	}
+	if err := fv.Validate(data); err != nil {
+		c.fail(newFormatRangeError(path, formatSchemaPath, schema.Format))
+	}
+}
+
+// isSoleType reports whether schema declares exactly t as its only type.
+func isSoleType(schema *spec.Schema, t string) bool {
+	return len(schema.Type) == 1 && schema.Type[0] == t
+}
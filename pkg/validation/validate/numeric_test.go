@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestIntegerFormatVocabulary(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		value         interface{}
+		expectSuccess bool
+	}{
+		{"byte valid", "byte", int64(200), true},
+		{"byte overflow", "byte", int64(256), false},
+		{"byte underflow", "byte", int64(-1), false},
+		{"uint valid", "uint", int64(42), true},
+		{"uint underflow", "uint", int64(-1), false},
+		{"int valid", "int", int64(-42), true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Format: tc.format}}
+			res := NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(tc.value)
+			if tc.expectSuccess {
+				assert.Empty(t, res.Errors)
+			} else {
+				assert.NotEmpty(t, res.Errors)
+			}
+		})
+	}
+}
+
+// TestInt64PrecisionAtMax ensures that a json.Number carrying math.MaxInt64
+// (not exactly representable as a float64) is still correctly judged to be
+// in range, rather than misjudged due to float64 rounding.
+func TestInt64PrecisionAtMax(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Format: "int64"}}
+	validator := NewSchemaValidator(schema, nil, "", strfmt.Default)
+
+	res := validator.Validate(json.Number("9223372036854775807")) // math.MaxInt64
+	assert.Empty(t, res.Errors)
+
+	res = validator.Validate(json.Number("9223372036854775808")) // math.MaxInt64 + 1
+	assert.NotEmpty(t, res.Errors)
+}
+
+// TestIsIntegral_MagnitudeGuard ensures a bare "type": "integer" (no format,
+// so nothing range-checks it) rejects a json.Number whose magnitude exceeds
+// any concrete integer type this package knows about, rather than trivially
+// accepting it because float64's == math.Trunc(f) check can't distinguish
+// "whole number" from "so large every float at this magnitude looks whole".
+func TestIsIntegral_MagnitudeGuard(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}}
+	validator := NewSchemaValidator(schema, nil, "", strfmt.Default)
+
+	res := validator.Validate(json.Number("9223372036854775807")) // math.MaxInt64, in range
+	assert.Empty(t, res.Errors)
+
+	res = validator.Validate(json.Number("1e300")) // far beyond any integer type
+	assert.NotEmpty(t, res.Errors)
+}
+
+func TestFormatEnforcementPolicy(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"number"}, Format: "int32"}}
+	value := float64(1 << 40) // well outside int32 range, but format is ignored/enforced per policy
+
+	lenient := NewSchemaValidator(schema, nil, "", strfmt.Default, WithFormatEnforcementPolicy(Lenient))
+	res := lenient.Validate(value)
+	assert.Empty(t, res.Errors, "Lenient should ignore the mismatched type/format pair")
+
+	strict := NewSchemaValidator(schema, nil, "", strfmt.Default, WithFormatEnforcementPolicy(Strict))
+	res = strict.Validate(value)
+	assert.NotEmpty(t, res.Errors, "Strict should reject the mismatched type/format pair")
+
+	warn := NewSchemaValidator(schema, nil, "", strfmt.Default, WithFormatEnforcementPolicy(Warn))
+	res = warn.Validate(value)
+	assert.Empty(t, res.Errors, "Warn should not fail validation")
+	assert.NotEmpty(t, res.Warnings, "Warn should record the mismatch as a warning")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+// SchemaValidatorOptions collects the configuration toggled via Option
+// functions passed to NewSchemaValidator or AgainstSchema.
+type SchemaValidatorOptions struct {
+	schemaLoader *SchemaLoader
+	multiError   bool
+	formatPolicy FormatEnforcementPolicy
+}
+
+// FormatEnforcementPolicy controls what happens when a schema's "format"
+// keyword disagrees with its "type" keyword, e.g.
+// {"type":"number","format":"int32"}.
+type FormatEnforcementPolicy int
+
+const (
+	// Lenient ignores the format when it disagrees with the declared type.
+	// This is the default.
+	Lenient FormatEnforcementPolicy = iota
+	// Strict reports a validation error for a mismatched type/format pair.
+	Strict
+	// Warn records a mismatched type/format pair as a non-fatal warning on
+	// Result.Warnings, without failing validation.
+	Warn
+)
+
+// Option configures a SchemaValidator (or a one-off AgainstSchema call).
+type Option func(*SchemaValidatorOptions)
+
+// WithSchemaLoader enables $ref resolution by consulting l whenever any
+// schema visited during validation -- the schema passed to Validate itself,
+// or any property, item or allOf/anyOf/oneOf branch schema reached while
+// walking it -- carries a $ref. Without this option, encountering a $ref
+// produces a structured validation error instead of resolving it.
+func WithSchemaLoader(l *SchemaLoader) Option {
+	return func(o *SchemaValidatorOptions) {
+		o.schemaLoader = l
+	}
+}
+
+// WithMultiError makes the validator visit every branch of the schema
+// (every property, pattern property, and array item) and collect every
+// failure it finds, rather than stopping at the first one. Combine the
+// result's errors with errors.As/Is, or type-assert to *MultiError to range
+// over them directly. This is the opposite of the default FailFast
+// behavior; see WithFailFast to restore it explicitly.
+func WithMultiError() Option {
+	return func(o *SchemaValidatorOptions) {
+		o.multiError = true
+	}
+}
+
+// WithFailFast stops validation at the first error found. This is the
+// default; the option exists to let callers restore it explicitly after
+// composing option sets.
+func WithFailFast() Option {
+	return func(o *SchemaValidatorOptions) {
+		o.multiError = false
+	}
+}
+
+// WithFormatEnforcementPolicy sets how a mismatched type/format pair is
+// treated (see FormatEnforcementPolicy). The default is Lenient.
+func WithFormatEnforcementPolicy(p FormatEnforcementPolicy) Option {
+	return func(o *SchemaValidatorOptions) {
+		o.formatPolicy = p
+	}
+}
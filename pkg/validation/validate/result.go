@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+// Result collects every problem found while validating a single document.
+type Result struct {
+	Errors   []error
+	Warnings []error
+}
+
+// IsValid reports whether no errors were recorded. A nil Result (as returned
+// for a nil *SchemaValidator) is always valid.
+func (r *Result) IsValid() bool {
+	return r == nil || len(r.Errors) == 0
+}
+
+// AddErrors appends one or more errors to the result.
+func (r *Result) AddErrors(errs ...error) {
+	r.Errors = append(r.Errors, errs...)
+}
+
+// AddWarnings appends one or more non-fatal warnings to the result.
+func (r *Result) AddWarnings(warnings ...error) {
+	r.Warnings = append(r.Warnings, warnings...)
+}
+
+// Merge folds another result's errors and warnings into this one.
+func (r *Result) Merge(other *Result) {
+	if other == nil {
+		return
+	}
+	r.Errors = append(r.Errors, other.Errors...)
+	r.Warnings = append(r.Warnings, other.Warnings...)
+}
+
+// AsError collapses the result's errors into a single error, or nil when the
+// result is valid. With exactly one error it is returned as-is; with more
+// than one (only possible under WithMultiError) it is wrapped in a
+// *MultiError so callers can still inspect every failure.
+func (r *Result) AsError() error {
+	if r.IsValid() {
+		return nil
+	}
+	if len(r.Errors) == 1 {
+		return r.Errors[0]
+	}
+	return &MultiError{Errors: r.Errors}
+}
+
+// ErrorsByCode returns the subset of the result's errors carrying the given
+// ErrorCode, in the order they were recorded. Errors produced outside this
+// package (which don't carry an ErrorCode) are skipped.
+func (r *Result) ErrorsByCode(code ErrorCode) []error {
+	var matched []error
+	for _, err := range r.Errors {
+		if verr, ok := err.(*ValidationError); ok && verr.Code == code {
+			matched = append(matched, err)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate implements a JSON-Schema validator for k8s.io/kube-openapi/pkg/validation/spec
+// schemas, in the spirit of (and largely following the behavior of) gojsonschema.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+// SchemaValidator validates data against a spec.Schema.
+//
+// A zero-value *SchemaValidator is never constructed directly: use
+// NewSchemaValidator, which returns nil when handed a nil schema so that
+// validation of an absent schema is a (nil-safe) no-op.
+type SchemaValidator struct {
+	Path         string
+	Schema       *spec.Schema
+	Root         interface{}
+	KnownFormats strfmt.Registry
+	Options      SchemaValidatorOptions
+}
+
+// NewSchemaValidator creates a new instance validating against the given schema.
+//
+// root is the document the schema was loaded from (used to resolve internal
+// "#/..." references); it defaults to schema itself when nil. formats is the
+// strfmt registry consulted for "format" keyword checks.
+//
+// NewSchemaValidator returns nil for a nil schema, and all of SchemaValidator's
+// methods are safe to call on a nil *SchemaValidator.
+func NewSchemaValidator(schema *spec.Schema, root interface{}, path string, formats strfmt.Registry, opts ...Option) *SchemaValidator {
+	if schema == nil {
+		return nil
+	}
+	if root == nil {
+		root = schema
+	}
+
+	s := &SchemaValidator{
+		Path:         path,
+		Schema:       schema,
+		Root:         root,
+		KnownFormats: formats,
+	}
+	for _, o := range opts {
+		o(&s.Options)
+	}
+	return s
+}
+
+// SetPath updates the instance path this validator reports errors against.
+func (s *SchemaValidator) SetPath(path string) {
+	if s == nil {
+		return
+	}
+	s.Path = path
+}
+
+// Applies tells whether this validator applies to source: SchemaValidator
+// only ever validates *spec.Schema-shaped sources, regardless of kind (kept
+// for parity with the other valueValidators this package will grow).
+func (s *SchemaValidator) Applies(source interface{}, kind reflect.Kind) bool {
+	_, ok := source.(*spec.Schema)
+	return ok
+}
+
+// Validate validates data against this validator's schema and returns a Result
+// describing every problem found (subject to the configured ErrorAggregation,
+// see WithFailFast/WithMultiError).
+//
+// Validate is safe to call on a nil *SchemaValidator; it then reports success,
+// matching the no-op semantics of NewSchemaValidator(nil, ...).
+func (s *SchemaValidator) Validate(data interface{}) *Result {
+	result := new(Result)
+	if s == nil || s.Schema == nil {
+		return result
+	}
+
+	ctx := &validateCtx{
+		validator: s,
+		result:    result,
+		root:      s.Root,
+	}
+	ctx.validateSchema(s.Path, "", s.Schema, data)
+	return result
+}
+
+// resolve follows a $ref on schema (if any) via the configured SchemaLoader,
+// returning a structured validation error instead of panicking when refs are
+// present but no loader was configured. root is the document schema itself
+// is to be resolved against. resolve is called at every point a schema is
+// about to be validated -- not only the top-level schema passed to
+// Validate, but every property, item and allOf/anyOf/oneOf branch -- since a
+// $ref can appear at any of those positions, and one left unresolved has
+// empty Type/Properties/etc. and so trivially (and wrongly) validates
+// anything. path and schemaPath are the instance/schema paths of the node
+// being resolved, used only to annotate a RefUnresolved error.
+//
+// resolve returns, alongside the dereferenced schema, the root that schema's
+// own nested refs must in turn be resolved against: root itself for a
+// ref-free schema or an internal "#/..." ref, or the externally-fetched
+// document once resolution crosses into one -- a ref nested inside that
+// document is relative to it, not to whatever document originally referenced
+// it. seen guards against reference cycles; it is keyed by the absolute URI
+// of each ref followed so far on the current path.
+func (s *SchemaValidator) resolve(path, schemaPath string, schema *spec.Schema, root interface{}, seen map[string]struct{}) (*spec.Schema, interface{}, error) {
+	if schema == nil || schema.Ref.String() == "" {
+		return schema, root, nil
+	}
+
+	ref := schema.Ref.String()
+	if s.Options.schemaLoader == nil {
+		return nil, nil, newRefUnresolvedError(path, schemaPath, ref, fmt.Errorf("no SchemaLoader configured: use WithSchemaLoader to enable $ref resolution"))
+	}
+
+	if _, ok := seen[ref]; ok {
+		// Cycle: stop recursing and validate against an empty schema rather
+		// than looping forever.
+		return &spec.Schema{}, root, nil
+	}
+	seen[ref] = struct{}{}
+
+	resolved, newRoot, err := s.Options.schemaLoader.Load(ref, root)
+	if err != nil {
+		return nil, nil, newRefUnresolvedError(path, schemaPath, ref, err)
+	}
+
+	// Follow nested refs inside the resolved document, against the
+	// document they were actually found in.
+	return s.resolve(path, schemaPath, resolved, newRoot, seen)
+}
+
+// AgainstSchema validates data against schema in one shot, returning a
+// single combined error (nil on success). Pass WithSchemaLoader to enable
+// $ref resolution; without it, a schema containing a $ref reports a
+// structured validation error instead of resolving it. By default the
+// first error found stops validation (FailFast); pass WithMultiError to
+// visit every property, pattern property and required field and collect
+// every failure into a *MultiError instead.
+func AgainstSchema(schema *spec.Schema, data interface{}, formats strfmt.Registry, opts ...Option) error {
+	validator := NewSchemaValidator(schema, nil, "", formats, opts...)
+	return validator.Validate(data).AsError()
+}
@@ -16,6 +16,7 @@ package validate
 
 import (
 	"encoding/json"
+	"errors"
 	"math"
 	"reflect"
 	"testing"
@@ -106,26 +107,267 @@ func TestSchemaValidator_PatternProperties(t *testing.T) {
 
 }
 
-func TestSchemaValidator_ReferencePanic(t *testing.T) {
-	assert.PanicsWithValue(t, `schema references not supported: http://localhost:1234/integer.json`, schemaRefValidator)
+func TestSchemaValidator_MultiErrorVsFailFast(t *testing.T) {
+	var schemaJSON = `
+{
+    "properties": {
+        "name": {
+            "type": "string",
+            "pattern": "^[A-Za-z]+$",
+            "minLength": 1
+        },
+        "age": {
+            "type": "integer",
+            "format": "int32"
+        },
+        "place": {
+            "type": "string",
+            "pattern": "^[A-Za-z]+$"
+        }
+    },
+    "required": [
+        "name"
+    ]
+}`
+
+	schema := new(spec.Schema)
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), schema))
+
+	// Three independent violations: name fails its pattern, age overflows
+	// int32, place has the wrong type.
+	input := map[string]interface{}{
+		"name":  "123",
+		"age":   int64(2147483648), // MaxInt32 + 1
+		"place": json.Number("5"),
+	}
+
+	fastErr := AgainstSchema(schema, input, strfmt.Default)
+	require.Error(t, fastErr)
+	var multi *MultiError
+	assert.False(t, errors.As(fastErr, &multi), "failfast should report a single error, not a MultiError")
+
+	multiErr := AgainstSchema(schema, input, strfmt.Default, WithMultiError())
+	require.Error(t, multiErr)
+	require.True(t, errors.As(multiErr, &multi), "multiError should report a *MultiError")
+	assert.Len(t, multi.Errors, 3)
+}
+
+func TestSchemaValidator_Items(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:  []string{"array"},
+			Items: &spec.SchemaOrArray{Schema: spec.StringProperty()},
+		},
+	}
+
+	assert.NoError(t, AgainstSchema(schema, []interface{}{"a", "b"}, strfmt.Default))
+	assert.Error(t, AgainstSchema(schema, []interface{}{"a", 1}, strfmt.Default))
+
+	multiErr := AgainstSchema(schema, []interface{}{1, 2, 3}, strfmt.Default, WithMultiError())
+	require.Error(t, multiErr)
+	var multi *MultiError
+	require.True(t, errors.As(multiErr, &multi))
+	assert.Len(t, multi.Errors, 3)
 }
 
-func schemaRefValidator() {
+func TestSchemaValidator_ItemsTuple(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"array"},
+			Items: &spec.SchemaOrArray{Schemas: []spec.Schema{
+				*spec.StringProperty(),
+				*spec.Int32Property(),
+			}},
+		},
+	}
+
+	assert.NoError(t, AgainstSchema(schema, []interface{}{"a", int64(1)}, strfmt.Default))
+	assert.Error(t, AgainstSchema(schema, []interface{}{int64(1), "a"}, strfmt.Default))
+
+	// An element past the tuple's length goes unchecked.
+	assert.NoError(t, AgainstSchema(schema, []interface{}{"a", int64(1), "anything"}, strfmt.Default))
+}
+
+func TestSchemaValidator_AllOf(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			AllOf: []spec.Schema{
+				{SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				{SchemaProps: spec.SchemaProps{Pattern: "^[a-z]+$"}},
+			},
+		},
+	}
+
+	assert.NoError(t, AgainstSchema(schema, "abc", strfmt.Default))
+	assert.Error(t, AgainstSchema(schema, "ABC", strfmt.Default), "fails the second branch's pattern")
+	assert.Error(t, AgainstSchema(schema, 42, strfmt.Default), "fails the first branch's type")
+}
+
+func TestSchemaValidator_AnyOf(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			AnyOf: []spec.Schema{
+				{SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}},
+			},
+		},
+	}
+
+	assert.NoError(t, AgainstSchema(schema, "a string", strfmt.Default))
+	assert.NoError(t, AgainstSchema(schema, int64(42), strfmt.Default))
+	assert.Error(t, AgainstSchema(schema, true, strfmt.Default), "matches neither branch")
+}
+
+func TestSchemaValidator_OneOf(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			OneOf: []spec.Schema{
+				{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}},
+				{SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Format: "int32"}},
+			},
+		},
+	}
+
+	// Matches only the bare-integer branch: int64 overflows the int32 one.
+	assert.NoError(t, AgainstSchema(schema, int64(1)<<40, strfmt.Default))
+	// Matches both branches: an int32-range value satisfies both.
+	assert.Error(t, AgainstSchema(schema, int64(42), strfmt.Default), "matching both branches violates oneOf")
+	// Matches neither branch.
+	assert.Error(t, AgainstSchema(schema, "nope", strfmt.Default))
+}
+
+func TestSchemaValidator_ReferenceUnresolved(t *testing.T) {
 	var schemaJSON = `
 {
     "$ref": "http://localhost:1234/integer.json"
 }`
 
 	schema := new(spec.Schema)
-	_ = json.Unmarshal([]byte(schemaJSON), schema)
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), schema))
 
 	var input map[string]interface{}
-
-	// ok
 	var inputJSON = `{"name": "Ivan","address-1": "sesame street"}`
-	_ = json.Unmarshal([]byte(inputJSON), &input)
-	// panics
-	_ = AgainstSchema(schema, input, strfmt.Default)
+	require.NoError(t, json.Unmarshal([]byte(inputJSON), &input))
+
+	// No SchemaLoader configured: a structured error, not a panic.
+	err := AgainstSchema(schema, input, strfmt.Default)
+	require.Error(t, err)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+}
+
+func TestSchemaValidator_ReferenceResolved(t *testing.T) {
+	var schemaJSON = `
+{
+    "$ref": "#/definitions/named"
+}`
+
+	schema := new(spec.Schema)
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), schema))
+
+	root := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Definitions: spec.Definitions{
+				"named": *spec.StringProperty(),
+			},
+		},
+	}
+
+	loader := NewSchemaLoader()
+	s := NewSchemaValidator(schema, root, "", strfmt.Default, WithSchemaLoader(loader))
+
+	assert.True(t, s.Validate("a string").IsValid())
+	assert.False(t, s.Validate(42).IsValid())
+}
+
+func TestSchemaValidator_ReferenceResolved_NestedUnderProperties(t *testing.T) {
+	// The dominant real-world shape: a $ref as the value of a property,
+	// not as the validated schema's own root.
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{
+				"name": {SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/named")}},
+			},
+			Required: []string{"name"},
+		},
+	}
+	root := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Definitions: spec.Definitions{
+				"named": *spec.StringProperty(),
+			},
+		},
+	}
+
+	loader := NewSchemaLoader()
+	s := NewSchemaValidator(schema, root, "", strfmt.Default, WithSchemaLoader(loader))
+
+	assert.True(t, s.Validate(map[string]interface{}{"name": "Ivan"}).IsValid())
+	assert.False(t, s.Validate(map[string]interface{}{"name": 42}).IsValid())
+}
+
+func TestSchemaValidator_ReferenceResolved_NestedUnderItems(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:  []string{"array"},
+			Items: &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/named")}}},
+		},
+	}
+	root := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Definitions: spec.Definitions{
+				"named": *spec.StringProperty(),
+			},
+		},
+	}
+
+	loader := NewSchemaLoader()
+	s := NewSchemaValidator(schema, root, "", strfmt.Default, WithSchemaLoader(loader))
+
+	assert.True(t, s.Validate([]interface{}{"a", "b"}).IsValid())
+	assert.False(t, s.Validate([]interface{}{"a", 1}).IsValid())
+}
+
+func TestSchemaValidator_ReferenceResolved_NestedUnderAllOf(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			AllOf: []spec.Schema{
+				{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/named")}},
+			},
+		},
+	}
+	root := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Definitions: spec.Definitions{
+				"named": *spec.StringProperty(),
+			},
+		},
+	}
+
+	loader := NewSchemaLoader()
+	s := NewSchemaValidator(schema, root, "", strfmt.Default, WithSchemaLoader(loader))
+
+	assert.True(t, s.Validate("a string").IsValid())
+	assert.False(t, s.Validate(42).IsValid())
+}
+
+func TestSchemaValidator_ReferenceCycle(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/a")}}
+	root := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Definitions: spec.Definitions{
+				"a": {SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/a")}},
+			},
+		},
+	}
+
+	loader := NewSchemaLoader()
+	s := NewSchemaValidator(schema, root, "", strfmt.Default, WithSchemaLoader(loader))
+
+	// A cycle must short-circuit rather than recurse forever.
+	assert.NotPanics(t, func() {
+		s.Validate("anything")
+	})
 }
 
 // Test edge cases in schemaValidator which are difficult
@@ -184,6 +426,43 @@ func TestSchemaValidator_EdgeCases(t *testing.T) {
 	assert.False(t, r.IsValid())
 }
 
+// BenchmarkAgainstSchema exercises the happy path (no errors recorded), run
+// with -benchmem to track allocations over time. ErrorCode values are plain
+// string constants rather than formatted per call, so a passing validation
+// doesn't pay for constructing error codes it never records.
+func BenchmarkAgainstSchema(b *testing.B) {
+	var schemaJSON = `
+{
+    "properties": {
+        "name": {
+            "type": "string",
+            "pattern": "^[A-Za-z]+$",
+            "minLength": 1
+        },
+        "age": {
+            "type": "integer",
+            "format": "int32"
+        }
+    },
+    "required": [
+        "name"
+    ]
+}`
+
+	schema := new(spec.Schema)
+	require.NoError(b, json.Unmarshal([]byte(schemaJSON), schema))
+
+	input := map[string]interface{}{"name": "Ivan", "age": int64(42)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := AgainstSchema(schema, input, strfmt.Default); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestNumericFormatEnforcement(t *testing.T) {
 	tests := []struct {
 		name          string
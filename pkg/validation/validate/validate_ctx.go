@@ -0,0 +1,346 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// validateCtx threads per-Validate-call state (the owning validator, and the
+// Result errors/warnings accumulate into) through the recursive schema walk.
+type validateCtx struct {
+	validator *SchemaValidator
+	result    *Result
+
+	// root is the document the schema currently being validated should
+	// resolve its own "#/..." refs against. It starts as the validator's
+	// Root and is updated (scoped to the recursive call it's threaded into)
+	// whenever resolve crosses into an externally-fetched document.
+	root interface{}
+
+	// patternCode selects which ErrorCode a pattern-keyword failure records:
+	// CodePattern for the plain "pattern" case, or CodePatternProperty while
+	// recursing into a schema matched through "patternProperties". It is
+	// transient, reset around each such recursive call.
+	patternCode ErrorCode
+}
+
+func (c *validateCtx) fail(err error) {
+	c.result.AddErrors(err)
+}
+
+// stopEarly reports whether the walk should stop recursing. By default
+// (FailFast) it stops as soon as one error has been recorded; under
+// WithMultiError it never stops early, so every property, pattern property
+// and required field gets visited and every failure is collected.
+func (c *validateCtx) stopEarly() bool {
+	if c.validator.Options.multiError {
+		return false
+	}
+	return len(c.result.Errors) > 0
+}
+
+// validateSchema is the single entry point for validating data against a
+// schema: every recursive call site (object properties, pattern properties,
+// additionalProperties, array items, allOf/anyOf/oneOf branches) comes
+// through here, so this is also the single place a $ref gets resolved. A
+// schema that is itself only a $ref (the common shape for refs nested under
+// properties/items rather than as the schema's own root) would otherwise
+// have empty Type/Properties/etc. and trivially accept any data.
+//
+// path is the instance (data) path reported on ValidationError.InstancePath;
+// schemaPath is the parallel path into the schema itself, reported on
+// ValidationError.SchemaPath.
+func (c *validateCtx) validateSchema(path, schemaPath string, schema *spec.Schema, data interface{}) {
+	if c.stopEarly() || schema == nil {
+		return
+	}
+
+	schema, root, err := c.validator.resolve(path, schemaPath, schema, c.root, map[string]struct{}{})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	if schema == nil {
+		return
+	}
+
+	prevRoot := c.root
+	c.root = root
+	defer func() { c.root = prevRoot }()
+
+	if !c.validateType(path, schemaPath, schema, data) {
+		return
+	}
+	if c.stopEarly() {
+		return
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		c.validateObject(path, schemaPath, schema, v)
+	case string:
+		c.validateString(path, schemaPath, schema, v)
+	case []interface{}:
+		c.validateArray(path, schemaPath, schema, v)
+	}
+	if c.stopEarly() {
+		return
+	}
+
+	c.validateComposition(path, schemaPath, schema, data)
+	if c.stopEarly() {
+		return
+	}
+
+	c.validateFormat(path, schemaPath, schema, data)
+}
+
+// validateType checks data's JSON type against schema.Type (when declared)
+// and records a type error if they disagree. It returns false when the walk
+// should not descend any further into data.
+func (c *validateCtx) validateType(path, schemaPath string, schema *spec.Schema, data interface{}) bool {
+	if len(schema.Type) == 0 {
+		return true
+	}
+
+	actual, numeric := kindOf(data)
+	for _, t := range schema.Type {
+		if t == actual {
+			return true
+		}
+		if t == "integer" && numeric && isIntegral(data) {
+			return true
+		}
+	}
+
+	c.fail(newTypeError(path, schemaPath, schema.Type, data))
+	return false
+}
+
+// kindOf reports the JSON Schema type name of data, and whether it is
+// numeric (in which case it may additionally satisfy "integer"). A
+// json.Number that does not parse as a float is reported as an invalid kind
+// that satisfies no declared type, rather than silently treated as valid.
+func kindOf(data interface{}) (kind string, numeric bool) {
+	switch v := data.(type) {
+	case nil:
+		return "null", false
+	case bool:
+		return "boolean", false
+	case string:
+		return "string", false
+	case map[string]interface{}:
+		return "object", false
+	case []interface{}:
+		return "array", false
+	case json.Number:
+		if _, err := v.Float64(); err != nil {
+			return "invalid-number", false
+		}
+		return "number", true
+	case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "number", true
+	default:
+		return "unknown", false
+	}
+}
+
+func (c *validateCtx) validateObject(path, schemaPath string, schema *spec.Schema, data map[string]interface{}) {
+	for _, req := range schema.Required {
+		if _, ok := data[req]; !ok {
+			c.fail(newRequiredError(joinPath(path, req), joinPath(schemaPath, "required")))
+		}
+		if c.stopEarly() {
+			return
+		}
+	}
+
+	type patternProperty struct {
+		re     *regexp.Regexp
+		schema *spec.Schema
+	}
+	var patternProps []patternProperty
+	for pattern, sub := range schema.PatternProperties {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		sub := sub
+		patternProps = append(patternProps, patternProperty{re: re, schema: &sub})
+	}
+
+	for key, value := range data {
+		if c.stopEarly() {
+			return
+		}
+		propPath := joinPath(path, key)
+
+		if propSchema, ok := schema.Properties[key]; ok {
+			propSchema := propSchema
+			prevCode := c.patternCode
+			c.patternCode = CodePattern
+			c.validateSchema(propPath, joinPath(joinPath(schemaPath, "properties"), key), &propSchema, value)
+			c.patternCode = prevCode
+			continue
+		}
+
+		matched := false
+		for _, pp := range patternProps {
+			if pp.re.MatchString(key) {
+				matched = true
+				prevCode := c.patternCode
+				c.patternCode = CodePatternProperty
+				c.validateSchema(propPath, joinPath(joinPath(schemaPath, "patternProperties"), key), pp.schema, value)
+				c.patternCode = prevCode
+			}
+		}
+		if matched {
+			continue
+		}
+
+		switch {
+		case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+			c.validateSchema(propPath, joinPath(schemaPath, "additionalProperties"), schema.AdditionalProperties.Schema, value)
+		case schema.AdditionalProperties != nil && !schema.AdditionalProperties.Allows:
+			c.fail(newAdditionalPropertiesError(propPath, joinPath(schemaPath, "additionalProperties"), key))
+		}
+	}
+}
+
+func (c *validateCtx) validateString(path, schemaPath string, schema *spec.Schema, data string) {
+	if schema.Pattern != "" {
+		if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(data) {
+			code := c.patternCode
+			if code == "" {
+				code = CodePattern
+			}
+			c.fail(newPatternError(path, joinPath(schemaPath, "pattern"), schema.Pattern, code))
+			return
+		}
+	}
+	if schema.MinLength != nil && int64(len(data)) < *schema.MinLength {
+		c.fail(newMinLengthError(path, joinPath(schemaPath, "minLength"), *schema.MinLength))
+	}
+}
+
+// validateArray walks schema.Items against each element of data: a single
+// schema applies to every element, while a tuple of schemas applies
+// positionally (elements past the tuple's length go unchecked, same as
+// schema.AdditionalItems being absent).
+func (c *validateCtx) validateArray(path, schemaPath string, schema *spec.Schema, data []interface{}) {
+	if schema.Items == nil {
+		return
+	}
+
+	itemsSchemaPath := joinPath(schemaPath, "items")
+	for i, value := range data {
+		if c.stopEarly() {
+			return
+		}
+
+		var itemSchema *spec.Schema
+		itemSchemaPath := itemsSchemaPath
+		switch {
+		case schema.Items.Schema != nil:
+			itemSchema = schema.Items.Schema
+		case i < len(schema.Items.Schemas):
+			itemSchema = &schema.Items.Schemas[i]
+			itemSchemaPath = joinIndex(itemsSchemaPath, i)
+		default:
+			continue
+		}
+
+		c.validateSchema(joinIndex(path, i), itemSchemaPath, itemSchema, value)
+	}
+}
+
+// validateComposition enforces allOf/anyOf/oneOf. allOf's sub-schemas are
+// validated in place, so their failures surface as ordinary nested errors;
+// anyOf and oneOf instead validate data against each branch in isolation
+// (failFast, regardless of the outer WithMultiError setting -- a branch not
+// taken has nothing useful to report) and record a single error describing
+// how many branches matched.
+func (c *validateCtx) validateComposition(path, schemaPath string, schema *spec.Schema, data interface{}) {
+	allOfPath := joinPath(schemaPath, "allOf")
+	for i, sub := range schema.AllOf {
+		if c.stopEarly() {
+			return
+		}
+		sub := sub
+		c.validateSchema(path, joinIndex(allOfPath, i), &sub, data)
+	}
+	if c.stopEarly() {
+		return
+	}
+
+	if len(schema.AnyOf) > 0 {
+		anyOfPath := joinPath(schemaPath, "anyOf")
+		matched := false
+		for i, sub := range schema.AnyOf {
+			sub := sub
+			if c.matches(path, joinIndex(anyOfPath, i), &sub, data) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			c.fail(newAnyOfError(path, anyOfPath))
+		}
+	}
+	if c.stopEarly() {
+		return
+	}
+
+	if len(schema.OneOf) > 0 {
+		oneOfPath := joinPath(schemaPath, "oneOf")
+		matches := 0
+		for i, sub := range schema.OneOf {
+			sub := sub
+			if c.matches(path, joinIndex(oneOfPath, i), &sub, data) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			c.fail(newOneOfError(path, oneOfPath, matches))
+		}
+	}
+}
+
+// matches reports whether data validates cleanly against schema, without
+// recording anything into the caller's Result. It inherits c's current root
+// so a $ref inside schema still resolves against the right document.
+func (c *validateCtx) matches(path, schemaPath string, schema *spec.Schema, data interface{}) bool {
+	probe := &validateCtx{validator: c.validator, result: new(Result), root: c.root}
+	probe.validateSchema(path, schemaPath, schema, data)
+	return probe.result.IsValid()
+}
+
+func joinIndex(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}